@@ -0,0 +1,161 @@
+package emit
+
+import (
+	"sync"
+
+	"github.com/coder/websocket"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Rooms returns the name of every room with at least one member.
+func (a *App) Rooms() []string {
+	var rooms []string
+	a.rooms.Range(func(key, value any) bool {
+		occupied := false
+		value.(*sync.Map).Range(func(_, _ any) bool {
+			occupied = true
+			return false
+		})
+		if occupied {
+			rooms = append(rooms, key.(string))
+		}
+		return true
+	})
+	return rooms
+}
+
+// RoomMembers returns the sockets currently in room.
+func (a *App) RoomMembers(room string) []*Socket {
+	if room != "" && room[0] != '#' {
+		room = "#" + room
+	}
+
+	var members []*Socket
+	roomMap, ok := a.rooms.Load(room)
+	if !ok {
+		return members
+	}
+
+	roomMap.(*sync.Map).Range(func(key, _ any) bool {
+		if socket, ok := a.sockets.Load(key); ok {
+			members = append(members, socket.(*Socket))
+		}
+		return true
+	})
+	return members
+}
+
+// SocketsWithTag returns every connected socket carrying tag.
+func (a *App) SocketsWithTag(tag string) []*Socket {
+	var sockets []*Socket
+	a.sockets.Range(func(_, value any) bool {
+		socket := value.(*Socket)
+		if socket.HasTag(tag) {
+			sockets = append(sockets, socket)
+		}
+		return true
+	})
+	return sockets
+}
+
+// SocketCount returns the number of sockets currently connected to this
+// node.
+func (a *App) SocketCount() int {
+	count := 0
+	a.sockets.Range(func(_, _ any) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+// Rooms returns the rooms s has joined.
+func (s *Socket) Rooms() []string {
+	var rooms []string
+	s.rooms.Range(func(key, _ any) bool {
+		rooms = append(rooms, key.(string))
+		return true
+	})
+	return rooms
+}
+
+// Tags returns the tags attached to s.
+func (s *Socket) Tags() []string {
+	var tags []string
+	s.tags.Range(func(key, _ any) bool {
+		tags = append(tags, key.(string))
+		return true
+	})
+	return tags
+}
+
+// EnableAdmin mounts an admin Namespace at prefix exposing room/socket
+// introspection and control (list rooms, kick a socket, force it to leave a
+// room, broadcast to any target) over the wire, gated by auth, plus a
+// Prometheus /metrics HTTP handler. auth may be nil to leave admin events
+// open to any connected socket.
+func (a *App) EnableAdmin(prefix string, auth Auth) *App {
+	admin := a.Namespace(prefix)
+
+	var requireAdmin MiddlewareFunc = func(req *Request, next NextFunc) error {
+		if auth != nil {
+			identity, err := auth.Authenticate(req.Socket.info, req.Socket)
+			if err != nil {
+				return &UserError{Code: "forbidden", Message: "admin authorization required"}
+			}
+			req.Socket.identity = &identity
+		}
+		return next()
+	}
+
+	admin.On("rooms", requireAdmin, func(req *Request) error {
+		return req.Reply(map[string]any{"rooms": a.Rooms()})
+	})
+
+	admin.On("members", requireAdmin, func(req *Request) error {
+		room, _ := req.Data["room"].(string)
+
+		var ids []string
+		for _, socket := range a.RoomMembers(room) {
+			ids = append(ids, socket.ID)
+		}
+		return req.Reply(map[string]any{"members": ids})
+	})
+
+	admin.On("kick", requireAdmin, func(req *Request) error {
+		socketID, _ := req.Data["socketId"].(string)
+		socket := a.GetSocket(socketID)
+		if socket == nil {
+			return &UserError{Code: "not_found", Message: "no such socket"}
+		}
+
+		socket.CloseWithReason(int(websocket.StatusNormalClosure), "kicked by admin")
+		return req.Reply(map[string]any{"ok": true})
+	})
+
+	admin.On("force-leave", requireAdmin, func(req *Request) error {
+		socketID, _ := req.Data["socketId"].(string)
+		room, _ := req.Data["room"].(string)
+
+		socket := a.GetSocket(socketID)
+		if socket == nil {
+			return &UserError{Code: "not_found", Message: "no such socket"}
+		}
+
+		socket.Leave(room)
+		return req.Reply(map[string]any{"ok": true})
+	})
+
+	admin.On("broadcast", requireAdmin, func(req *Request) error {
+		event, _ := req.Data["event"].(string)
+		to, _ := req.Data["to"].(string)
+		data, _ := req.Data["data"].(map[string]any)
+
+		a.Broadcast(event, data, to)
+		return req.Reply(map[string]any{"ok": true})
+	})
+
+	a.mux.Handle("/metrics", promhttp.HandlerFor(a.metrics.registry, promhttp.HandlerOpts{}))
+
+	return a
+}
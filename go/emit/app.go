@@ -5,25 +5,193 @@ import (
 	"log"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/coder/websocket"
+	"github.com/google/uuid"
 )
 
+// defaultOverflowTimeout bounds how long OverflowBlock waits for room in a
+// full sendChan before giving up.
+const defaultOverflowTimeout = 5 * time.Second
+
 type App struct {
-	handlers   sync.Map
-	rooms      sync.Map
-	sockets    sync.Map
-	middleware []MiddlewareFunc
-	server     *http.Server
-	ctx        context.Context
-	cancel     context.CancelFunc
+	handlers        sync.Map
+	rooms           sync.Map
+	sockets         sync.Map
+	middleware      []MiddlewareFunc
+	server          *http.Server
+	ctx             context.Context
+	cancel          context.CancelFunc
+	nodeID          NodeID
+	backplane       Backplane
+	codecs          map[string]Codec
+	codecOrder      []string
+	defaultCodec    Codec
+	pingInterval    time.Duration
+	pongTimeout     time.Duration
+	writeTimeout    time.Duration
+	overflowPolicy  OverflowPolicy
+	overflowTimeout time.Duration
+	auth            Auth
+	allowedOrigins  []string
+	mux             *http.ServeMux
+	metrics         *metrics
 }
 
 func New() *App {
 	ctx, cancel := context.WithCancel(context.Background())
-	return &App{
+	defaultCodec := JSONCodec{}
+
+	a := &App{
 		ctx:    ctx,
 		cancel: cancel,
+		nodeID: NodeID(uuid.New().String()),
+		codecs: map[string]Codec{
+			defaultCodec.Subprotocol():    defaultCodec,
+			ProtobufCodec{}.Subprotocol(): ProtobufCodec{},
+			MsgpackCodec{}.Subprotocol():  MsgpackCodec{},
+		},
+		codecOrder: []string{
+			defaultCodec.Subprotocol(),
+			ProtobufCodec{}.Subprotocol(),
+			MsgpackCodec{}.Subprotocol(),
+		},
+		defaultCodec:    defaultCodec,
+		overflowPolicy:  OverflowDisconnect,
+		overflowTimeout: defaultOverflowTimeout,
+		mux:             http.NewServeMux(),
+		metrics:         newMetrics(),
+	}
+
+	a.mux.HandleFunc("/", a.handleWebSocket)
+	return a
+}
+
+// WithCodec registers a Codec, making its subprotocol negotiable at
+// handshake time. Registering a codec under SubprotocolJSON overrides the
+// default codec used for clients that don't request a subprotocol. The
+// order codecs are registered in is the order they're offered to clients
+// during negotiation, so calling this moves codec to the back of the
+// preference list unless it's already registered.
+func (a *App) WithCodec(codec Codec) *App {
+	if _, ok := a.codecs[codec.Subprotocol()]; !ok {
+		a.codecOrder = append(a.codecOrder, codec.Subprotocol())
+	}
+	a.codecs[codec.Subprotocol()] = codec
+	if codec.Subprotocol() == a.defaultCodec.Subprotocol() {
+		a.defaultCodec = codec
+	}
+	return a
+}
+
+// WithPingInterval enables keepalive pings on this interval. A socket that
+// doesn't see the corresponding pong within the pong timeout is disconnected.
+func (a *App) WithPingInterval(d time.Duration) *App {
+	a.pingInterval = d
+	return a
+}
+
+// WithPongTimeout sets how long a socket waits for a pong after sending a
+// keepalive ping before it is considered dead.
+func (a *App) WithPongTimeout(d time.Duration) *App {
+	a.pongTimeout = d
+	return a
+}
+
+// WithWriteTimeout bounds how long a single write to a socket's connection
+// may take before it is considered dead.
+func (a *App) WithWriteTimeout(d time.Duration) *App {
+	a.writeTimeout = d
+	return a
+}
+
+// WithOverflowPolicy controls what happens when a socket's outbound buffer
+// is full because the client is reading slower than the server is emitting.
+func (a *App) WithOverflowPolicy(policy OverflowPolicy) *App {
+	a.overflowPolicy = policy
+	return a
+}
+
+// WithOverflowTimeout sets how long OverflowBlock waits for buffer room
+// before giving up and disconnecting. Only used when the overflow policy is
+// OverflowBlock.
+func (a *App) WithOverflowTimeout(d time.Duration) *App {
+	a.overflowTimeout = d
+	return a
+}
+
+// WithAuth gates the WebSocket upgrade on auth, rejecting it with 401 if
+// Authenticate fails. The resulting Identity is available from Socket and
+// Request via Identity().
+func (a *App) WithAuth(auth Auth) *App {
+	a.auth = auth
+	return a
+}
+
+// WithAllowedOrigins restricts which Origin headers may upgrade to a
+// WebSocket connection. Without it, origin checking is disabled
+// (websocket.AcceptOptions.InsecureSkipVerify), which is only appropriate
+// for local development.
+func (a *App) WithAllowedOrigins(origins []string) *App {
+	a.allowedOrigins = origins
+	return a
+}
+
+// pongTimeoutOrDefault falls back to the ping interval itself when a ping
+// interval is configured without an explicit pong timeout.
+func (a *App) pongTimeoutOrDefault() time.Duration {
+	if a.pongTimeout > 0 {
+		return a.pongTimeout
+	}
+	return a.pingInterval
+}
+
+// WithBackplane wires a cluster Backplane into the app so that
+// App.Broadcast to rooms, tags, and all sockets reaches a fleet of emit.gg
+// servers instead of staying local to this process. Socket.Emit and
+// Socket.Request are not published and reach only sockets on this node.
+func (a *App) WithBackplane(bp Backplane) *App {
+	a.backplane = bp
+	if err := bp.Subscribe(a.dispatchEnvelope); err != nil {
+		log.Printf("Failed to subscribe to backplane: %v", err)
+	}
+	return a
+}
+
+// dispatchEnvelope delivers an Envelope received from the backplane to any
+// matching local sockets. Envelopes this node published itself are skipped
+// to avoid delivery loops.
+func (a *App) dispatchEnvelope(env Envelope) {
+	if env.Origin == a.nodeID {
+		return
+	}
+
+	msg := Message{Event: env.Event, Data: env.Data}
+
+	switch env.Kind {
+	case EnvelopeBroadcast:
+		a.sockets.Range(func(_, value any) bool {
+			value.(*Socket).emit(msg)
+			return true
+		})
+	case EnvelopeRoom:
+		if roomMap, ok := a.rooms.Load(env.Target); ok {
+			roomMap.(*sync.Map).Range(func(key, _ any) bool {
+				if socket, ok := a.sockets.Load(key); ok {
+					socket.(*Socket).emit(msg)
+				}
+				return true
+			})
+		}
+	case EnvelopeTag:
+		a.sockets.Range(func(_, value any) bool {
+			socket := value.(*Socket)
+			if socket.HasTag(env.Target) {
+				socket.emit(msg)
+			}
+			return true
+		})
 	}
 }
 
@@ -100,6 +268,26 @@ func (a *App) Broadcast(event string, data map[string]any, to string) {
 		for _, socket := range targets {
 			socket.emit(msg)
 		}
+
+		if a.backplane != nil {
+			kind := EnvelopeBroadcast
+			switch to[0] {
+			case '#':
+				kind = EnvelopeRoom
+			case '*':
+				kind = EnvelopeTag
+			}
+
+			if err := a.backplane.Publish(Envelope{
+				Kind:   kind,
+				Target: to,
+				Origin: a.nodeID,
+				Event:  event,
+				Data:   data,
+			}); err != nil {
+				log.Printf("Failed to publish to backplane: %v", err)
+			}
+		}
 	}
 }
 
@@ -111,11 +299,9 @@ func (a *App) GetSocket(socketID string) *Socket {
 }
 
 func (a *App) Listen(addr string) error {
-	mux := http.NewServeMux()
-	mux.HandleFunc("/", a.handleWebSocket)
 	a.server = &http.Server{
 		Addr:    addr,
-		Handler: mux,
+		Handler: a.mux,
 	}
 	log.Printf("emit.gg server listening on %s", addr)
 	return a.server.ListenAndServe()
@@ -123,6 +309,13 @@ func (a *App) Listen(addr string) error {
 
 func (a *App) Close() error {
 	a.cancel()
+
+	if a.backplane != nil {
+		if err := a.backplane.Close(); err != nil {
+			log.Printf("Failed to close backplane: %v", err)
+		}
+	}
+
 	if a.server != nil {
 		return a.server.Shutdown(context.Background())
 	}
@@ -130,8 +323,35 @@ func (a *App) Close() error {
 }
 
 func (a *App) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	// The socket is constructed before the connection is accepted so that
+	// Auth.Authenticate has somewhere to stash identity/claims via
+	// socket.data. Its conn is filled in only once auth passes.
+	socket := newSocket(nil, a, r, a.defaultCodec)
+
+	if a.auth != nil {
+		identity, err := a.auth.Authenticate(r, socket)
+		if err != nil {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		socket.identity = &identity
+	}
+
+	// Built from codecOrder, not by ranging over the codecs map, so
+	// negotiation preference is deterministic: coder/websocket's
+	// selectSubprotocol picks the first server-offered entry the client also
+	// sent, and Go's map iteration order is randomized per run.
+	subprotocols := make([]string, 0, len(a.codecOrder))
+	for _, subprotocol := range a.codecOrder {
+		if _, ok := a.codecs[subprotocol]; ok {
+			subprotocols = append(subprotocols, subprotocol)
+		}
+	}
+
 	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
-		InsecureSkipVerify: true,
+		InsecureSkipVerify: a.allowedOrigins == nil,
+		OriginPatterns:     a.allowedOrigins,
+		Subprotocols:       subprotocols,
 	})
 
 	if err != nil {
@@ -139,8 +359,13 @@ func (a *App) handleWebSocket(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	socket := newSocket(conn, a, r)
+	socket.conn = conn
+	if c, ok := a.codecs[conn.Subprotocol()]; ok {
+		socket.codec = c
+	}
+
 	a.sockets.Store(socket.ID, socket)
+	a.metrics.connections.Inc()
 
 	if entry, ok := a.handlers.Load("@connection"); ok {
 		req := &Request{
@@ -0,0 +1,38 @@
+package emit
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Identity is the authenticated principal attached to a Socket after a
+// successful Auth.Authenticate call.
+type Identity struct {
+	ID     string
+	Tags   []string
+	Claims map[string]any
+}
+
+// Auth authenticates an incoming WebSocket upgrade before the @connection
+// handler fires. Returning an error rejects the upgrade with 401.
+type Auth interface {
+	Authenticate(r *http.Request, s *Socket) (Identity, error)
+}
+
+// AuthFunc adapts a plain function to the Auth interface.
+type AuthFunc func(r *http.Request, s *Socket) (Identity, error)
+
+func (f AuthFunc) Authenticate(r *http.Request, s *Socket) (Identity, error) {
+	return f(r, s)
+}
+
+// RequireTag returns middleware that rejects a request with a UserError
+// unless the socket carries tag.
+func (a *App) RequireTag(tag string) MiddlewareFunc {
+	return func(req *Request, next NextFunc) error {
+		if !req.Socket.HasTag(tag) {
+			return &UserError{Code: "forbidden", Message: fmt.Sprintf("requires tag %q", tag)}
+		}
+		return next()
+	}
+}
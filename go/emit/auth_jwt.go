@@ -0,0 +1,65 @@
+package emit
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTAuth verifies a bearer JWT (HS*, RS*, or ES* - whatever ValidMethods
+// allows and KeyFunc accepts) supplied via the Authorization header or a
+// "token" query parameter, and copies its claims into socket.data.
+type JWTAuth struct {
+	// KeyFunc resolves the verification key for a token, mirroring
+	// jwt.Keyfunc. Use it to select a key by algorithm/kid.
+	KeyFunc jwt.Keyfunc
+
+	// ValidMethods restricts which signing algorithms (e.g. "RS256",
+	// "HS256") are accepted, and is required. Without it, an attacker who
+	// knows an RS*/ES* public key used by KeyFunc can forge an HS* token
+	// signed with that key as the HMAC secret and pass verification - the
+	// classic JWT algorithm-confusion attack.
+	ValidMethods []string
+}
+
+// NewJWTAuth returns a JWTAuth that verifies tokens with keyFunc, accepting
+// only the signing methods listed in validMethods (e.g. []string{"RS256"}).
+func NewJWTAuth(keyFunc jwt.Keyfunc, validMethods []string) *JWTAuth {
+	return &JWTAuth{KeyFunc: keyFunc, ValidMethods: validMethods}
+}
+
+func (a *JWTAuth) Authenticate(r *http.Request, s *Socket) (Identity, error) {
+	if len(a.ValidMethods) == 0 {
+		return Identity{}, fmt.Errorf("ValidMethods must be set")
+	}
+
+	raw := bearerToken(r)
+	if raw == "" {
+		return Identity{}, fmt.Errorf("missing bearer token")
+	}
+
+	token, err := jwt.Parse(raw, a.KeyFunc, jwt.WithValidMethods(a.ValidMethods))
+	if err != nil {
+		return Identity{}, fmt.Errorf("invalid token: %w", err)
+	}
+	if !token.Valid {
+		return Identity{}, fmt.Errorf("invalid token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return Identity{}, fmt.Errorf("unsupported claims type %T", token.Claims)
+	}
+
+	identity := Identity{Claims: claims}
+	if sub, ok := claims["sub"].(string); ok {
+		identity.ID = sub
+	}
+
+	for key, value := range claims {
+		s.data.Store(key, value)
+	}
+
+	return identity, nil
+}
@@ -0,0 +1,38 @@
+package emit
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// bearerToken extracts a bearer token from the Authorization header or,
+// failing that, a "token" query parameter.
+func bearerToken(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		if token, ok := strings.CutPrefix(auth, "Bearer "); ok {
+			return token
+		}
+	}
+	return r.URL.Query().Get("token")
+}
+
+// TokenAuth authenticates by looking up a bearer token (Authorization
+// header or "token" query parameter) against a fixed set of known tokens.
+type TokenAuth struct {
+	Tokens map[string]Identity
+}
+
+func (a *TokenAuth) Authenticate(r *http.Request, s *Socket) (Identity, error) {
+	token := bearerToken(r)
+	if token == "" {
+		return Identity{}, fmt.Errorf("missing bearer token")
+	}
+
+	identity, ok := a.Tokens[token]
+	if !ok {
+		return Identity{}, fmt.Errorf("invalid token")
+	}
+
+	return identity, nil
+}
@@ -0,0 +1,39 @@
+package emit
+
+// NodeID identifies a single emit.gg process within a cluster.
+type NodeID string
+
+// EnvelopeKind describes how an Envelope should be routed once it reaches
+// a remote node.
+type EnvelopeKind string
+
+const (
+	EnvelopeBroadcast EnvelopeKind = "broadcast"
+	EnvelopeRoom      EnvelopeKind = "room"
+	EnvelopeTag       EnvelopeKind = "tag"
+)
+
+// Envelope is the unit of work exchanged between nodes over a Backplane.
+// Target is interpreted according to Kind: a room name (with leading "#")
+// for EnvelopeRoom, a tag (with leading "*") for EnvelopeTag, or ignored for
+// EnvelopeBroadcast.
+//
+// There is deliberately no per-socket Envelope kind: a Socket wraps the one
+// websocket.Conn physically held by this node, so a socket ID can never be
+// addressed from another node in the cluster.
+type Envelope struct {
+	Kind   EnvelopeKind
+	Target string
+	Origin NodeID
+	Event  string
+	Data   map[string]any
+}
+
+// Backplane fans Envelopes out to every other node in a cluster so that
+// App.Broadcast reaches sockets connected to other processes, not just the
+// local one.
+type Backplane interface {
+	Publish(env Envelope) error
+	Subscribe(handler func(Envelope)) error
+	Close() error
+}
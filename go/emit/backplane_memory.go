@@ -0,0 +1,76 @@
+package emit
+
+import "sync"
+
+// MemoryBus is an in-process fan-out bus shared by one or more
+// MemoryBackplane instances. It exists so tests (and single-process
+// deployments) can exercise the clustering code paths without a real NATS
+// server.
+type MemoryBus struct {
+	mu        sync.Mutex
+	listeners []chan Envelope
+}
+
+// NewMemoryBus creates an empty bus. Multiple MemoryBackplanes constructed
+// with the same bus observe each other's published Envelopes.
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{}
+}
+
+func (b *MemoryBus) subscribe() chan Envelope {
+	ch := make(chan Envelope, 256)
+	b.mu.Lock()
+	b.listeners = append(b.listeners, ch)
+	b.mu.Unlock()
+	return ch
+}
+
+func (b *MemoryBus) publish(env Envelope) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, ch := range b.listeners {
+		select {
+		case ch <- env:
+		default:
+		}
+	}
+}
+
+// MemoryBackplane is a Backplane backed by a MemoryBus. It never crosses a
+// process boundary, which makes it a convenient stand-in for NATSBackplane
+// in tests.
+type MemoryBackplane struct {
+	bus  *MemoryBus
+	done chan struct{}
+}
+
+// NewMemoryBackplane returns a Backplane publishing to and subscribing from
+// bus.
+func NewMemoryBackplane(bus *MemoryBus) *MemoryBackplane {
+	return &MemoryBackplane{bus: bus, done: make(chan struct{})}
+}
+
+func (m *MemoryBackplane) Publish(env Envelope) error {
+	m.bus.publish(env)
+	return nil
+}
+
+func (m *MemoryBackplane) Subscribe(handler func(Envelope)) error {
+	ch := m.bus.subscribe()
+	go func() {
+		for {
+			select {
+			case env := <-ch:
+				handler(env)
+			case <-m.done:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (m *MemoryBackplane) Close() error {
+	close(m.done)
+	return nil
+}
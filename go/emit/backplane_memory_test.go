@@ -0,0 +1,73 @@
+package emit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryBackplaneRoundTrip(t *testing.T) {
+	bus := NewMemoryBus()
+	publisher := NewMemoryBackplane(bus)
+	subscriber := NewMemoryBackplane(bus)
+	defer publisher.Close()
+	defer subscriber.Close()
+
+	received := make(chan Envelope, 1)
+	if err := subscriber.Subscribe(func(env Envelope) {
+		received <- env
+	}); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	want := Envelope{
+		Kind:   EnvelopeRoom,
+		Target: "#lobby",
+		Origin: NodeID("node-a"),
+		Event:  "chat",
+		Data:   map[string]any{"text": "hi"},
+	}
+	if err := publisher.Publish(want); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	select {
+	case got := <-received:
+		if got.Kind != want.Kind || got.Target != want.Target || got.Origin != want.Origin || got.Event != want.Event {
+			t.Fatalf("got %+v, want %+v", got, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for envelope")
+	}
+}
+
+func TestMemoryBackplaneCloseStopsDelivery(t *testing.T) {
+	bus := NewMemoryBus()
+	publisher := NewMemoryBackplane(bus)
+	subscriber := NewMemoryBackplane(bus)
+	defer publisher.Close()
+
+	received := make(chan Envelope, 1)
+	if err := subscriber.Subscribe(func(env Envelope) {
+		received <- env
+	}); err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	if err := subscriber.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Give the subscriber goroutine a chance to observe done before
+	// publishing, so a flake here would mean done isn't honored rather than
+	// a race in the test itself.
+	time.Sleep(10 * time.Millisecond)
+
+	if err := publisher.Publish(Envelope{Kind: EnvelopeBroadcast, Origin: NodeID("node-a")}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+
+	select {
+	case env := <-received:
+		t.Fatalf("unexpected envelope delivered after Close: %+v", env)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,73 @@
+package emit
+
+import (
+	"encoding/json"
+
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	natsSubjectRoom      = "emit.room."
+	natsSubjectTag       = "emit.tag."
+	natsSubjectBroadcast = "emit.broadcast"
+	natsSubjectAll       = "emit.>"
+)
+
+// NATSBackplane is a Backplane backed by a NATS connection, letting a fleet
+// of emit.gg servers share room, tag, and broadcast envelopes across
+// processes. There is no per-socket subject; direct sends stay local.
+type NATSBackplane struct {
+	conn *nats.Conn
+	sub  *nats.Subscription
+}
+
+// NewNATSBackplane connects to the NATS server at url.
+func NewNATSBackplane(url string) (*NATSBackplane, error) {
+	conn, err := nats.Connect(url)
+	if err != nil {
+		return nil, err
+	}
+	return &NATSBackplane{conn: conn}, nil
+}
+
+func natsSubject(env Envelope) string {
+	switch env.Kind {
+	case EnvelopeRoom:
+		return natsSubjectRoom + env.Target
+	case EnvelopeTag:
+		return natsSubjectTag + env.Target
+	default:
+		return natsSubjectBroadcast
+	}
+}
+
+func (n *NATSBackplane) Publish(env Envelope) error {
+	data, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+	return n.conn.Publish(natsSubject(env), data)
+}
+
+func (n *NATSBackplane) Subscribe(handler func(Envelope)) error {
+	sub, err := n.conn.Subscribe(natsSubjectAll, func(msg *nats.Msg) {
+		var env Envelope
+		if err := json.Unmarshal(msg.Data, &env); err != nil {
+			return
+		}
+		handler(env)
+	})
+	if err != nil {
+		return err
+	}
+	n.sub = sub
+	return nil
+}
+
+func (n *NATSBackplane) Close() error {
+	if n.sub != nil {
+		_ = n.sub.Unsubscribe()
+	}
+	n.conn.Close()
+	return nil
+}
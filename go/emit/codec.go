@@ -0,0 +1,15 @@
+package emit
+
+import "github.com/coder/websocket"
+
+// Codec marshals and unmarshals the wire representation of a Message.
+// Selecting a Codec lets a deployment trade JSON's debuggability for a
+// smaller, faster binary framing.
+type Codec interface {
+	Marshal(msg Message) ([]byte, websocket.MessageType, error)
+	Unmarshal(data []byte, msgType websocket.MessageType, msg *Message) error
+
+	// Subprotocol is the Sec-WebSocket-Protocol token clients negotiate at
+	// handshake time to select this codec, e.g. "emit.json.v1".
+	Subprotocol() string
+}
@@ -0,0 +1,27 @@
+package emit
+
+import (
+	"encoding/json"
+
+	"github.com/coder/websocket"
+)
+
+// SubprotocolJSON is the Sec-WebSocket-Protocol token for JSONCodec, the
+// default codec.
+const SubprotocolJSON = "emit.json.v1"
+
+// JSONCodec is the original, human-readable wire format.
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(msg Message) ([]byte, websocket.MessageType, error) {
+	data, err := json.Marshal(msg)
+	return data, websocket.MessageText, err
+}
+
+func (JSONCodec) Unmarshal(data []byte, _ websocket.MessageType, msg *Message) error {
+	return json.Unmarshal(data, msg)
+}
+
+func (JSONCodec) Subprotocol() string {
+	return SubprotocolJSON
+}
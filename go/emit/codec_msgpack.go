@@ -0,0 +1,26 @@
+package emit
+
+import (
+	"github.com/coder/websocket"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// SubprotocolMsgpack is the Sec-WebSocket-Protocol token for MsgpackCodec.
+const SubprotocolMsgpack = "emit.msgpack.v1"
+
+// MsgpackCodec trades JSON's readability for MessagePack's denser binary
+// framing, useful for bandwidth-constrained mobile clients.
+type MsgpackCodec struct{}
+
+func (MsgpackCodec) Marshal(msg Message) ([]byte, websocket.MessageType, error) {
+	data, err := msgpack.Marshal(msg)
+	return data, websocket.MessageBinary, err
+}
+
+func (MsgpackCodec) Unmarshal(data []byte, _ websocket.MessageType, msg *Message) error {
+	return msgpack.Unmarshal(data, msg)
+}
+
+func (MsgpackCodec) Subprotocol() string {
+	return SubprotocolMsgpack
+}
@@ -0,0 +1,56 @@
+package emit
+
+import "testing"
+
+func TestCodecRoundTrip(t *testing.T) {
+	codecs := map[string]Codec{
+		SubprotocolJSON:     JSONCodec{},
+		SubprotocolProtobuf: ProtobufCodec{},
+		SubprotocolMsgpack:  MsgpackCodec{},
+	}
+
+	msg := Message{
+		Type:  "event",
+		Event: "chat.message",
+		Data:  map[string]any{"text": "hello", "count": float64(3)},
+		AckID: "ack-123",
+	}
+
+	for name, codec := range codecs {
+		t.Run(name, func(t *testing.T) {
+			data, _, err := codec.Marshal(msg)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+
+			var got Message
+			if err := codec.Unmarshal(data, 0, &got); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+
+			if got.Type != msg.Type || got.Event != msg.Event || got.AckID != msg.AckID {
+				t.Fatalf("got %+v, want %+v", got, msg)
+			}
+			if got.Data["text"] != msg.Data["text"] || got.Data["count"] != msg.Data["count"] {
+				t.Fatalf("got data %+v, want %+v", got.Data, msg.Data)
+			}
+		})
+	}
+}
+
+func TestCodecSubprotocols(t *testing.T) {
+	cases := []struct {
+		codec Codec
+		want  string
+	}{
+		{JSONCodec{}, SubprotocolJSON},
+		{ProtobufCodec{}, SubprotocolProtobuf},
+		{MsgpackCodec{}, SubprotocolMsgpack},
+	}
+
+	for _, c := range cases {
+		if got := c.codec.Subprotocol(); got != c.want {
+			t.Errorf("Subprotocol() = %q, want %q", got, c.want)
+		}
+	}
+}
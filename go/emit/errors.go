@@ -0,0 +1,85 @@
+package emit
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/coder/websocket"
+)
+
+// closeCoder is implemented by errors that know which WebSocket close code
+// best describes them. See closeCode.
+type closeCoder interface {
+	CloseCode() websocket.StatusCode
+}
+
+// ProtocolError indicates the wire-level message couldn't be understood,
+// e.g. a codec unmarshal failure. It maps to websocket.StatusProtocolError.
+type ProtocolError struct {
+	Err error
+}
+
+func (e *ProtocolError) Error() string { return "protocol error: " + e.Err.Error() }
+func (e *ProtocolError) Unwrap() error { return e.Err }
+func (e *ProtocolError) CloseCode() websocket.StatusCode {
+	return websocket.StatusProtocolError
+}
+
+// HandlerError wraps a recovered panic from a handler or middleware so one
+// misbehaving handler can't crash its goroutine.
+type HandlerError struct {
+	Event string
+	Err   error
+}
+
+func (e *HandlerError) Error() string {
+	return fmt.Sprintf("handler error in %q: %v", e.Event, e.Err)
+}
+func (e *HandlerError) Unwrap() error { return e.Err }
+func (e *HandlerError) CloseCode() websocket.StatusCode {
+	return websocket.StatusInternalError
+}
+
+// TimeoutError indicates a Socket.Request did not receive an ack within its
+// timeout.
+type TimeoutError struct {
+	Event string
+}
+
+func (e *TimeoutError) Error() string { return "request timeout: " + e.Event }
+func (e *TimeoutError) CloseCode() websocket.StatusCode {
+	return websocket.StatusNormalClosure
+}
+
+// UserError is returned by a handler to signal an expected, client-facing
+// failure (bad input, not authorized, etc.) rather than a bug, so it should
+// not be treated as a crash.
+type UserError struct {
+	Code    string
+	Message string
+}
+
+func (e *UserError) Error() string { return e.Message }
+func (e *UserError) CloseCode() websocket.StatusCode {
+	return websocket.StatusNormalClosure
+}
+
+// closeCode resolves the WebSocket close code to use for err, defaulting to
+// StatusInternalError for errors that don't opt into the closeCoder mapping.
+func closeCode(err error) websocket.StatusCode {
+	var coder closeCoder
+	if errors.As(err, &coder) {
+		return coder.CloseCode()
+	}
+	return websocket.StatusInternalError
+}
+
+// isTerminal reports whether err indicates the connection is no longer in a
+// trustworthy state and should be closed with closeCode(err), as opposed to
+// an expected, recoverable failure (UserError, TimeoutError) that's only
+// worth surfacing via @error/ack.
+func isTerminal(err error) bool {
+	var protocolErr *ProtocolError
+	var handlerErr *HandlerError
+	return errors.As(err, &protocolErr) || errors.As(err, &handlerErr)
+}
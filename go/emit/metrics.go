@@ -0,0 +1,45 @@
+package emit
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// metrics holds the Prometheus instrumentation for a single App. It uses a
+// private registry rather than the global one so multiple Apps (e.g. in
+// tests) don't collide on metric names.
+type metrics struct {
+	registry    *prometheus.Registry
+	connections prometheus.Counter
+	messagesIn  prometheus.Counter
+	messagesOut prometheus.Counter
+	dropped     prometheus.Counter
+	ackLatency  prometheus.Histogram
+}
+
+func newMetrics() *metrics {
+	m := &metrics{
+		registry: prometheus.NewRegistry(),
+		connections: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "emit_connections_total",
+			Help: "Total number of accepted WebSocket connections.",
+		}),
+		messagesIn: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "emit_messages_in_total",
+			Help: "Total number of messages received from clients.",
+		}),
+		messagesOut: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "emit_messages_out_total",
+			Help: "Total number of messages sent to clients.",
+		}),
+		dropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "emit_messages_dropped_total",
+			Help: "Total number of messages dropped because a socket's sendChan was full.",
+		}),
+		ackLatency: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "emit_ack_latency_seconds",
+			Help:    "Round-trip latency of Socket.Request acks.",
+			Buckets: prometheus.DefBuckets,
+		}),
+	}
+
+	m.registry.MustRegister(m.connections, m.messagesIn, m.messagesOut, m.dropped, m.ackLatency)
+	return m
+}
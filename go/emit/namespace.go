@@ -32,3 +32,14 @@ func (ns *Namespace) Namespace(prefix string) *Namespace {
 		prefix: ns.prefix + prefix,
 	}
 }
+
+// RequireAuth returns middleware that rejects a request with a UserError
+// unless the socket has an authenticated Identity.
+func (ns *Namespace) RequireAuth() MiddlewareFunc {
+	return func(req *Request, next NextFunc) error {
+		if req.Identity() == nil {
+			return &UserError{Code: "unauthorized", Message: "authentication required"}
+		}
+		return next()
+	}
+}
@@ -52,3 +52,9 @@ func (r *Request) Untag(name string) {
 func (r *Request) Broadcast(event string, data map[string]any, to string) {
 	r.App.Broadcast(event, data, to)
 }
+
+// Identity returns the authenticated principal attached to the underlying
+// socket, or nil if it hasn't been authenticated.
+func (r *Request) Identity() *Identity {
+	return r.Socket.Identity()
+}
@@ -2,7 +2,6 @@ package emit
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
@@ -17,6 +16,7 @@ type Socket struct {
 	ID              string
 	conn            *websocket.Conn
 	app             *App
+	codec           Codec
 	rooms           sync.Map
 	tags            sync.Map
 	data            sync.Map
@@ -25,15 +25,25 @@ type Socket struct {
 	ctx             context.Context
 	cancel          context.CancelFunc
 	info            *http.Request
+	closeOnce       sync.Once
+	identity        *Identity
 }
 
-func newSocket(conn *websocket.Conn, app *App, req *http.Request) *Socket {
+// Identity returns the authenticated principal attached during the
+// handshake by the app's Auth, or nil if no Auth is configured or the
+// socket hasn't been authenticated.
+func (s *Socket) Identity() *Identity {
+	return s.identity
+}
+
+func newSocket(conn *websocket.Conn, app *App, req *http.Request, codec Codec) *Socket {
 	ctx, cancel := context.WithCancel(app.ctx)
 
 	return &Socket{
 		ID:       uuid.New().String(),
 		conn:     conn,
 		app:      app,
+		codec:    codec,
 		sendChan: make(chan Message, 256),
 		ctx:      ctx,
 		cancel:   cancel,
@@ -49,15 +59,21 @@ func (s *Socket) readPump() {
 		case <-s.ctx.Done():
 			return
 		default:
-			_, data, err := s.conn.Read(s.ctx)
+			readCtx, cancel := s.readContext()
+
+			msgType, data, err := s.conn.Read(readCtx)
+			cancel()
 			if err != nil {
 				return
 			}
+			s.app.metrics.messagesIn.Inc()
 
 			var msg Message
-			if err := json.Unmarshal(data, &msg); err != nil {
-				s.handleError(err, nil)
-				continue
+			if err := s.codec.Unmarshal(data, msgType, &msg); err != nil {
+				protoErr := &ProtocolError{Err: err}
+				s.handleError(protoErr, nil)
+				s.CloseWithReason(int(closeCode(protoErr)), protoErr.Error())
+				return
 			}
 
 			go s.handleMessage(&msg)
@@ -65,23 +81,57 @@ func (s *Socket) readPump() {
 	}
 }
 
+// readContext bounds a single Read call with an idle deadline spanning one
+// ping interval plus a pong timeout, so a peer that goes completely silent
+// (no messages, no pong) is eventually disconnected. Returns s.ctx unchanged
+// when keepalive pings are disabled.
+func (s *Socket) readContext() (context.Context, context.CancelFunc) {
+	if s.app.pingInterval == 0 {
+		return s.ctx, func() {}
+	}
+	return context.WithTimeout(s.ctx, s.app.pingInterval+s.app.pongTimeoutOrDefault())
+}
+
 func (s *Socket) writePump() {
 	defer s.disconnect()
 
+	var tick <-chan time.Time
+	if s.app.pingInterval > 0 {
+		ticker := time.NewTicker(s.app.pingInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
 	for {
 		select {
 		case <-s.ctx.Done():
 			return
+		case <-tick:
+			pingCtx, cancel := context.WithTimeout(s.ctx, s.app.pongTimeoutOrDefault())
+			err := s.conn.Ping(pingCtx)
+			cancel()
+			if err != nil {
+				return
+			}
 		case msg := <-s.sendChan:
-			data, err := json.Marshal(msg)
+			data, wsType, err := s.codec.Marshal(msg)
 			if err != nil {
 				log.Printf("Failed to marshal message: %v", err)
 				continue
 			}
 
-			if err := s.conn.Write(s.ctx, websocket.MessageText, data); err != nil {
+			writeCtx := s.ctx
+			cancel := context.CancelFunc(func() {})
+			if s.app.writeTimeout > 0 {
+				writeCtx, cancel = context.WithTimeout(s.ctx, s.app.writeTimeout)
+			}
+
+			err = s.conn.Write(writeCtx, wsType, data)
+			cancel()
+			if err != nil {
 				return
 			}
+			s.app.metrics.messagesOut.Inc()
 
 		}
 	}
@@ -109,7 +159,7 @@ func (s *Socket) handleMessage(msg *Message) {
 		ctx:    s.ctx,
 	}
 
-	s.runMiddleware(s.app.middleware, req, func() error {
+	err := s.runMiddleware(s.app.middleware, req, func() error {
 		if entry, ok := s.app.handlers.Load("@any"); ok {
 			entry.(*handlerEntry).handler(req)
 		}
@@ -129,9 +179,28 @@ func (s *Socket) handleMessage(msg *Message) {
 		}
 		return nil
 	})
+
+	if err != nil {
+		s.handleError(err, req)
+		req.Reply(map[string]any{"error": err.Error(), "code": int(closeCode(err))})
+
+		if isTerminal(err) {
+			s.CloseWithReason(int(closeCode(err)), err.Error())
+		}
+	}
 }
 
-func (s *Socket) runMiddleware(middleware []MiddlewareFunc, req *Request, done func() error) error {
+// runMiddleware runs the middleware chain, recovering any panic from a
+// handler or middleware and turning it into a HandlerError instead of
+// crashing the socket's goroutine. The caller is responsible for surfacing
+// the returned error (handleMessage fires @error and acks it back).
+func (s *Socket) runMiddleware(middleware []MiddlewareFunc, req *Request, done func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &HandlerError{Event: req.Event, Err: fmt.Errorf("%v", r)}
+		}
+	}()
+
 	if len(middleware) == 0 {
 		return done()
 	}
@@ -150,13 +219,48 @@ func (s *Socket) runMiddleware(middleware []MiddlewareFunc, req *Request, done f
 	return run(0)
 }
 
+// emit queues msg for delivery, honoring the app's OverflowPolicy if
+// sendChan is already full so a slow client can't exhaust server memory.
 func (s *Socket) emit(msg Message) {
 	select {
 	case s.sendChan <- msg:
+		return
 	case <-s.ctx.Done():
+		return
+	default:
+	}
+
+	switch s.app.overflowPolicy {
+	case OverflowDropOldest:
+		select {
+		case <-s.sendChan:
+			s.app.metrics.dropped.Inc()
+		default:
+		}
+		select {
+		case s.sendChan <- msg:
+		case <-s.ctx.Done():
+		}
+	case OverflowBlock:
+		timer := time.NewTimer(s.app.overflowTimeout)
+		defer timer.Stop()
+		select {
+		case s.sendChan <- msg:
+		case <-s.ctx.Done():
+		case <-timer.C:
+			s.app.metrics.dropped.Inc()
+			s.disconnect()
+		}
+	default: // OverflowDisconnect
+		s.app.metrics.dropped.Inc()
+		s.disconnect()
 	}
 }
 
+// Emit sends event to this socket only. Unlike App.Broadcast, this never
+// goes through the backplane: a Socket wraps the one websocket.Conn
+// physically held by this node, so there's nothing for another node to
+// address.
 func (s *Socket) Emit(event string, data map[string]any) {
 	s.emit(Message{Event: event, Data: data})
 }
@@ -168,6 +272,7 @@ func (s *Socket) Request(event string, data map[string]any, timeout time.Duratio
 
 	ackID := uuid.New().String()
 	replyChan := make(chan map[string]any, 1)
+	start := time.Now()
 
 	timer := time.AfterFunc(timeout, func() {
 		s.pendingRequests.Delete(ackID)
@@ -187,9 +292,10 @@ func (s *Socket) Request(event string, data map[string]any, timeout time.Duratio
 
 	reply, ok := <-replyChan
 	if !ok {
-		return nil, fmt.Errorf("request timeout: %s", event)
+		return nil, &TimeoutError{Event: event}
 	}
 
+	s.app.metrics.ackLatency.Observe(time.Since(start).Seconds())
 	return reply, nil
 
 }
@@ -243,38 +349,63 @@ func (s *Socket) HasTag(name string) bool {
 }
 
 func (s *Socket) disconnect() {
-	s.cancel()
-	s.conn.Close(websocket.StatusNormalClosure, "")
+	s.disconnectWithReason(websocket.StatusNormalClosure, "")
+}
 
-	s.rooms.Range(func(key, _ any) bool {
-		s.Leave(key.(string))
-		return true
-	})
+// CloseWithReason closes the socket's connection with a specific WebSocket
+// close code and reason text, e.g. one produced by closeCode(err).
+func (s *Socket) CloseWithReason(code int, text string) error {
+	return s.disconnectWithReason(websocket.StatusCode(code), text)
+}
 
-	s.app.sockets.Delete(s.ID)
+func (s *Socket) disconnectWithReason(code websocket.StatusCode, text string) error {
+	var closeErr error
 
-	if entry, ok := s.app.handlers.Load("@disconnect"); ok {
-		req := &Request{
-			Event:  "@disconnect",
-			Socket: s,
-			App:    s.app,
-			ctx:    s.ctx,
+	s.closeOnce.Do(func() {
+		s.cancel()
+		if s.conn != nil {
+			closeErr = s.conn.Close(code, text)
 		}
-		entry.(*handlerEntry).handler(req)
-	}
-}
 
-func (s *Socket) handleError(err error, req *Request) {
-	if entry, ok := s.app.handlers.Load("@error"); ok {
-		if req == nil {
-			req = &Request{
+		s.rooms.Range(func(key, _ any) bool {
+			s.Leave(key.(string))
+			return true
+		})
+
+		s.app.sockets.Delete(s.ID)
+
+		if entry, ok := s.app.handlers.Load("@disconnect"); ok {
+			req := &Request{
+				Event:  "@disconnect",
 				Socket: s,
 				App:    s.app,
 				ctx:    s.ctx,
 			}
 			entry.(*handlerEntry).handler(req)
-		} else {
-			log.Printf("Error: %v", err)
 		}
+	})
+
+	return closeErr
+}
+
+// handleError logs err and, if an @error handler is registered, invokes it
+// with the error's message and close code attached to the request data.
+func (s *Socket) handleError(err error, req *Request) {
+	log.Printf("Error: %v", err)
+
+	entry, ok := s.app.handlers.Load("@error")
+	if !ok {
+		return
 	}
+
+	if req == nil {
+		req = &Request{
+			Socket: s,
+			App:    s.app,
+			ctx:    s.ctx,
+		}
+	}
+	req.Data = map[string]any{"error": err.Error(), "code": int(closeCode(err))}
+
+	entry.(*handlerEntry).handler(req)
 }
@@ -0,0 +1,66 @@
+package emit
+
+import "testing"
+
+// newTestSocket builds a Socket wired to app but without a real
+// websocket.Conn, sufficient for exercising emit's overflow handling.
+// disconnectWithReason is nil-safe specifically so OverflowDisconnect can be
+// exercised this way too.
+func newTestSocket(app *App) *Socket {
+	return newSocket(nil, app, nil, JSONCodec{})
+}
+
+func TestEmitOverflowDropOldest(t *testing.T) {
+	app := New()
+	app.overflowPolicy = OverflowDropOldest
+	s := newTestSocket(app)
+	s.sendChan = make(chan Message, 2)
+
+	s.emit(Message{Event: "first"})
+	s.emit(Message{Event: "second"})
+	s.emit(Message{Event: "third"})
+
+	first := <-s.sendChan
+	second := <-s.sendChan
+
+	if first.Event != "second" || second.Event != "third" {
+		t.Fatalf("expected oldest message dropped, got %q then %q", first.Event, second.Event)
+	}
+}
+
+func TestEmitOverflowDisconnect(t *testing.T) {
+	app := New() // OverflowDisconnect is the default policy.
+	s := newTestSocket(app)
+	s.sendChan = make(chan Message, 1)
+	app.sockets.Store(s.ID, s)
+
+	s.emit(Message{Event: "first"})
+	s.emit(Message{Event: "second"}) // sendChan is full: should disconnect s.
+
+	select {
+	case <-s.ctx.Done():
+	default:
+		t.Fatal("expected socket to be disconnected when sendChan overflows")
+	}
+
+	if _, ok := app.sockets.Load(s.ID); ok {
+		t.Fatal("expected disconnected socket to be removed from app.sockets")
+	}
+}
+
+func TestEmitFitsWithinBuffer(t *testing.T) {
+	app := New()
+	s := newTestSocket(app)
+	s.sendChan = make(chan Message, 1)
+
+	s.emit(Message{Event: "only"})
+
+	select {
+	case msg := <-s.sendChan:
+		if msg.Event != "only" {
+			t.Fatalf("got %q, want %q", msg.Event, "only")
+		}
+	default:
+		t.Fatal("expected message to be buffered")
+	}
+}
@@ -2,7 +2,7 @@ package emit
 
 import "time"
 
-type HanddlerFunc func(*Request) error
+type HandlerFunc func(*Request) error
 type MiddlewareFunc func(*Request, NextFunc) error
 type NextFunc func() error
 
@@ -22,3 +22,19 @@ type pendingRequest struct {
 	replyChan chan map[string]any
 	timer     *time.Timer
 }
+
+// OverflowPolicy controls what happens when a Socket's sendChan is full,
+// i.e. the client is reading slower than the server is emitting.
+type OverflowPolicy int
+
+const (
+	// OverflowDisconnect closes the socket, the safest default since it
+	// bounds the memory a single slow client can pin.
+	OverflowDisconnect OverflowPolicy = iota
+	// OverflowDropOldest discards the oldest buffered message to make room
+	// for the new one.
+	OverflowDropOldest
+	// OverflowBlock waits up to the app's overflow timeout for room to free
+	// up before giving up and disconnecting.
+	OverflowBlock
+)